@@ -1,214 +1,1147 @@
-package main
-
-import (
-	"context"
-	"encoding/json"
-	"log"
-	"net/http"
-	"strconv"
-	"time"
-
-	"github.com/aws/aws-lambda-go/events"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-)
-
-const tableName = "Orders"
-
-var dbClient *dynamodb.Client
-
-// Order model
-type Order struct {
-	OrderID      string `json:"orderId"`
-	CustomerName string `json:"customerName"`
-	Product      string `json:"product"`
-	Quantity     int    `json:"quantity"`
-	Status       string `json:"status"`
-	CreatedAt    string `json:"createdAt"`
-}
-
-func init() {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
-	if err != nil {
-		log.Fatalf("failed to load AWS config: %v", err)
-	}
-	dbClient = dynamodb.NewFromConfig(cfg)
-}
-
-// Lambda handler
-func handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-
-	switch req.HTTPMethod {
-
-	case http.MethodPost:
-		return createOrder(ctx, req)
-
-	case http.MethodGet:
-		// /orders/{orderId}
-		if orderId, ok := req.PathParameters["orderId"]; ok && orderId != "" {
-			return getOrderByID(ctx, orderId)
-		}
-		// /orders
-		return getAllOrders(ctx)
-
-	default:
-		return events.APIGatewayProxyResponse{
-			StatusCode: http.StatusMethodNotAllowed,
-			Body:       "Method not allowed",
-		}, nil
-	}
-}
-
-// ---------- POST: Create Order ----------
-func createOrder(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-
-	var order Order
-	if err := json.Unmarshal([]byte(req.Body), &order); err != nil {
-		return response(http.StatusBadRequest, "Invalid request body")
-	}
-
-	if order.CreatedAt == "" {
-		order.CreatedAt = time.Now().UTC().Format(time.RFC3339)
-	}
-
-	_, err := dbClient.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(tableName),
-		Item: map[string]types.AttributeValue{
-			"orderId":      &types.AttributeValueMemberS{Value: order.OrderID},
-			"customerName": &types.AttributeValueMemberS{Value: order.CustomerName},
-			"product":      &types.AttributeValueMemberS{Value: order.Product},
-			"quantity":     &types.AttributeValueMemberN{Value: intToString(order.Quantity)},
-			"status":       &types.AttributeValueMemberS{Value: order.Status},
-			"createdAt":    &types.AttributeValueMemberS{Value: order.CreatedAt},
-		},
-	})
-	if err != nil {
-		log.Println("PutItem error:", err)
-		return response(http.StatusInternalServerError, "Failed to create order")
-	}
-
-	return response(http.StatusCreated, "Order created successfully")
-}
-
-// ---------- GET: All Orders ----------
-func getAllOrders(ctx context.Context) (events.APIGatewayProxyResponse, error) {
-
-	out, err := dbClient.Scan(ctx, &dynamodb.ScanInput{
-		TableName: aws.String(tableName),
-	})
-	if err != nil {
-		log.Println("Scan error:", err)
-		return response(http.StatusInternalServerError, "Failed to fetch orders")
-	}
-
-	orders := []Order{}
-	for _, item := range out.Items {
-		orders = append(orders, mapToOrder(item))
-	}
-
-	body, _ := json.Marshal(orders)
-	return events.APIGatewayProxyResponse{
-		StatusCode: http.StatusOK,
-		Body:       string(body),
-	}, nil
-}
-
-// ---------- GET: Order by ID ----------
-func getOrderByID(ctx context.Context, orderId string) (events.APIGatewayProxyResponse, error) {
-
-	out, err := dbClient.Query(ctx, &dynamodb.QueryInput{
-		TableName:              aws.String(tableName),
-		KeyConditionExpression: aws.String("orderId = :oid"),
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":oid": &types.AttributeValueMemberS{Value: orderId},
-		},
-	})
-	if err != nil {
-		log.Println("Query error:", err)
-		return response(http.StatusInternalServerError, "Failed to fetch order")
-	}
-
-	if len(out.Items) == 0 {
-		return response(http.StatusNotFound, "Order not found")
-	}
-
-	orders := []Order{}
-	for _, item := range out.Items {
-		orders = append(orders, mapToOrder(item))
-	}
-
-	body, _ := json.Marshal(orders)
-	return events.APIGatewayProxyResponse{
-		StatusCode: http.StatusOK,
-		Body:       string(body),
-	}, nil
-}
-
-// ---------- Helpers ----------
-func response(status int, msg string) (events.APIGatewayProxyResponse, error) {
-	body, _ := json.Marshal(map[string]string{"message": msg})
-	return events.APIGatewayProxyResponse{
-		StatusCode: status,
-		Body:       string(body),
-	}, nil
-}
-
-func intToString(i int) string {
-	return strconv.Itoa(i)
-}
-
-func mapToOrder(item map[string]types.AttributeValue) Order {
-	return Order{
-		OrderID:      item["orderId"].(*types.AttributeValueMemberS).Value,
-		CustomerName: item["customerName"].(*types.AttributeValueMemberS).Value,
-		Product:      item["product"].(*types.AttributeValueMemberS).Value,
-		Quantity:     atoi(item["quantity"].(*types.AttributeValueMemberN).Value),
-		Status:       item["status"].(*types.AttributeValueMemberS).Value,
-		CreatedAt:    item["createdAt"].(*types.AttributeValueMemberS).Value,
-	}
-}
-
-func atoi(s string) int {
-	i, _ := strconv.Atoi(s)
-	return i
-}
-
-func main() {
-	lambda.Start(handler)
-
-	// test for getting order by order_id
-	// event := events.APIGatewayProxyRequest{
-	// 	HTTPMethod: "GET",
-	// 	PathParameters: map[string]string{
-	// 		"orderId": "d9dcde4b-b163-4176-8d1a-f49d270a2f5e",
-	// 	},
-	// }
-
-	// to get all orders
-	// event := events.APIGatewayProxyRequest{
-	// 	HTTPMethod: "GET",
-	// }
-
-	//to post an order
-	// event := events.APIGatewayProxyRequest{
-	// 	HTTPMethod: "POST",
-	// 	Body: `{
-	// 		"orderId": "20221",
-	// 		"customerName": "Siri",
-	// 		"product": "Lunch Box",
-	// 		"quantity": 1,
-	// 		"status": "CREATED"
-	// 	}`,
-	// }
-
-	// resp, err := handler(context.Background(), event)
-	// if err != nil {
-	// 	log.Fatal(err)
-	// }
-
-	// log.Println("STATUS:", resp.StatusCode)
-	// log.Println("BODY:", resp.Body)
-}
+//go:build !websocket
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+const tableName = "Orders"
+
+// DynamoDBAPI is the subset of the aws-sdk-go-v2 DynamoDB client that the
+// handlers depend on. Typing it as an interface instead of *dynamodb.Client
+// lets tests inject fakes and lets production code swap in an Amazon DAX
+// client for read-through caching without touching handler code.
+type DynamoDBAPI interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+}
+
+var _ DynamoDBAPI = (*dynamodb.Client)(nil)
+
+var dbClient DynamoDBAPI
+
+// SNSAPI is the subset of the aws-sdk-go-v2 SNS client used to publish order
+// lifecycle events. Typing it as an interface keeps it test-fakeable the
+// same way DynamoDBAPI is.
+type SNSAPI interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+var _ SNSAPI = (*sns.Client)(nil)
+
+var (
+	snsClient       SNSAPI
+	orderEventTopic = os.Getenv("ORDER_EVENTS_TOPIC_ARN")
+)
+
+// contextKey namespaces values the handler context carries, keyed off of
+// context.Context rather than package globals so request handling can be
+// parallelized in tests with distinct fake clients per test.
+type contextKey string
+
+const (
+	dbClientKey        contextKey = "dbClient"
+	tableNameKey       contextKey = "tableName"
+	snsClientKey       contextKey = "snsClient"
+	orderEventTopicKey contextKey = "orderEventTopic"
+)
+
+// withHandlerContext attaches the DynamoDB client, table name, SNS client,
+// and event topic ARN that handler and its helpers pull off ctx instead of
+// reading package globals.
+func withHandlerContext(ctx context.Context, client DynamoDBAPI, table string, snsAPI SNSAPI, topic string) context.Context {
+	ctx = context.WithValue(ctx, dbClientKey, client)
+	ctx = context.WithValue(ctx, tableNameKey, table)
+	ctx = context.WithValue(ctx, snsClientKey, snsAPI)
+	ctx = context.WithValue(ctx, orderEventTopicKey, topic)
+	return ctx
+}
+
+func clientFromContext(ctx context.Context) DynamoDBAPI {
+	client, _ := ctx.Value(dbClientKey).(DynamoDBAPI)
+	return client
+}
+
+func tableNameFromContext(ctx context.Context) string {
+	table, _ := ctx.Value(tableNameKey).(string)
+	return table
+}
+
+func snsClientFromContext(ctx context.Context) SNSAPI {
+	client, _ := ctx.Value(snsClientKey).(SNSAPI)
+	return client
+}
+
+func orderEventTopicFromContext(ctx context.Context) string {
+	topic, _ := ctx.Value(orderEventTopicKey).(string)
+	return topic
+}
+
+// publishOrderEvent notifies websocket subscribers of an order lifecycle
+// change. It is a no-op when no topic is configured, so the feature can be
+// enabled per-environment purely via ORDER_EVENTS_TOPIC_ARN.
+func publishOrderEvent(ctx context.Context, eventType string, order Order) {
+	client := snsClientFromContext(ctx)
+	topic := orderEventTopicFromContext(ctx)
+	if client == nil || topic == "" {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":  eventType,
+		"order": order,
+	})
+	if err != nil {
+		log.Println("order event marshal error:", err)
+		return
+	}
+
+	if _, err := client.Publish(ctx, &sns.PublishInput{
+		TopicArn: aws.String(topic),
+		Message:  aws.String(string(payload)),
+	}); err != nil {
+		log.Println("SNS publish error:", err)
+	}
+}
+
+// Order model
+type Order struct {
+	OrderID      string `json:"orderId"`
+	CustomerName string `json:"customerName"`
+	Product      string `json:"product"`
+	Quantity     int    `json:"quantity"`
+	Status       string `json:"status"`
+	CreatedAt    string `json:"createdAt"`
+	Version      int    `json:"version"`
+}
+
+func init() {
+	cfg, err := config.LoadDefaultConfig(context.TODO())
+	if err != nil {
+		log.Fatalf("failed to load AWS config: %v", err)
+	}
+	dbClient = dynamodb.NewFromConfig(cfg)
+	snsClient = sns.NewFromConfig(cfg)
+}
+
+// Lambda handler
+func handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	switch req.HTTPMethod {
+
+	case http.MethodPost:
+		switch {
+		case strings.HasSuffix(req.Path, ":batch"):
+			return batchCreateOrders(ctx, req)
+		case strings.HasSuffix(req.Path, ":transact"):
+			return transactOrders(ctx, req)
+		default:
+			return createOrder(ctx, req)
+		}
+
+	case http.MethodGet:
+		// /orders/{orderId}
+		if orderId, ok := req.PathParameters["orderId"]; ok && orderId != "" {
+			return getOrderByID(ctx, orderId)
+		}
+		// /orders
+		return listOrders(ctx, req)
+
+	case http.MethodPut:
+		orderId, ok := req.PathParameters["orderId"]
+		if !ok || orderId == "" {
+			return apiError(http.StatusBadRequest, "orderId is required")
+		}
+		return updateOrder(ctx, orderId, req)
+
+	case http.MethodPatch:
+		orderId, ok := req.PathParameters["orderId"]
+		if !ok || orderId == "" {
+			return apiError(http.StatusBadRequest, "orderId is required")
+		}
+		return patchOrder(ctx, orderId, req)
+
+	case http.MethodDelete:
+		orderId, ok := req.PathParameters["orderId"]
+		if !ok || orderId == "" {
+			return apiError(http.StatusBadRequest, "orderId is required")
+		}
+		return deleteOrder(ctx, orderId, req)
+
+	case http.MethodOptions:
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusNoContent,
+			Headers:    corsHeaders(),
+		}, nil
+
+	default:
+		return apiError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// ---------- POST: Create Order ----------
+func createOrder(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	client := clientFromContext(ctx)
+	table := tableNameFromContext(ctx)
+
+	var order Order
+	if err := json.Unmarshal([]byte(req.Body), &order); err != nil {
+		return apiError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if details := validateOrder(order); len(details) > 0 {
+		return apiError(http.StatusBadRequest, "Order failed validation", details...)
+	}
+
+	if order.CreatedAt == "" {
+		order.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	order.Version = 1
+
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(table),
+		Item: map[string]types.AttributeValue{
+			"orderId":      &types.AttributeValueMemberS{Value: order.OrderID},
+			"customerName": &types.AttributeValueMemberS{Value: order.CustomerName},
+			"product":      &types.AttributeValueMemberS{Value: order.Product},
+			"quantity":     &types.AttributeValueMemberN{Value: intToString(order.Quantity)},
+			"status":       &types.AttributeValueMemberS{Value: order.Status},
+			"createdAt":    &types.AttributeValueMemberS{Value: order.CreatedAt},
+			"version":      &types.AttributeValueMemberN{Value: intToString(order.Version)},
+		},
+	})
+	if err != nil {
+		log.Println("PutItem error:", err)
+		return apiError(http.StatusInternalServerError, "Failed to create order")
+	}
+
+	publishOrderEvent(ctx, "order.created", order)
+	return response(http.StatusCreated, "Order created successfully")
+}
+
+// ---------- PUT: Full Replace ----------
+func updateOrder(ctx context.Context, orderId string, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	client := clientFromContext(ctx)
+	table := tableNameFromContext(ctx)
+
+	var order Order
+	if err := json.Unmarshal([]byte(req.Body), &order); err != nil {
+		return apiError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if order.Version <= 0 {
+		return apiError(http.StatusBadRequest, "version is required")
+	}
+	order.OrderID = orderId
+
+	if details := validateOrder(order); len(details) > 0 {
+		return apiError(http.StatusBadRequest, "Order failed validation", details...)
+	}
+
+	newVersion := order.Version + 1
+
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(table),
+		Item: map[string]types.AttributeValue{
+			"orderId":      &types.AttributeValueMemberS{Value: order.OrderID},
+			"customerName": &types.AttributeValueMemberS{Value: order.CustomerName},
+			"product":      &types.AttributeValueMemberS{Value: order.Product},
+			"quantity":     &types.AttributeValueMemberN{Value: intToString(order.Quantity)},
+			"status":       &types.AttributeValueMemberS{Value: order.Status},
+			"createdAt":    &types.AttributeValueMemberS{Value: order.CreatedAt},
+			"version":      &types.AttributeValueMemberN{Value: intToString(newVersion)},
+		},
+		ConditionExpression: aws.String("attribute_exists(orderId) AND version = :v"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v": &types.AttributeValueMemberN{Value: intToString(order.Version)},
+		},
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return apiError(http.StatusConflict, "Order was modified by another request")
+		}
+		log.Println("PutItem error:", err)
+		return apiError(http.StatusInternalServerError, "Failed to update order")
+	}
+
+	order.Version = newVersion
+	publishOrderEvent(ctx, "order.updated", order)
+	return response(http.StatusOK, "Order updated successfully")
+}
+
+// ---------- PATCH: Partial Update ----------
+func patchOrder(ctx context.Context, orderId string, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	client := clientFromContext(ctx)
+	table := tableNameFromContext(ctx)
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(req.Body), &fields); err != nil {
+		return apiError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	rawVersion, ok := fields["version"]
+	if !ok {
+		return apiError(http.StatusBadRequest, "version is required")
+	}
+	version, ok := rawVersion.(float64)
+	if !ok || version <= 0 {
+		return apiError(http.StatusBadRequest, "version must be a positive number")
+	}
+	delete(fields, "version")
+	delete(fields, "orderId")
+
+	setClause, exprNames, exprValues, err := buildUpdateExpression(fields)
+	if err != nil {
+		return apiError(http.StatusBadRequest, err.Error())
+	}
+	updateExpr := "SET version = :newVersion"
+	if setClause != "" {
+		updateExpr += ", " + setClause
+	}
+	exprValues[":v"] = &types.AttributeValueMemberN{Value: intToString(int(version))}
+	exprValues[":newVersion"] = &types.AttributeValueMemberN{Value: intToString(int(version) + 1)}
+
+	out, err := client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(table),
+		Key: map[string]types.AttributeValue{
+			"orderId": &types.AttributeValueMemberS{Value: orderId},
+		},
+		UpdateExpression:          aws.String(updateExpr),
+		ConditionExpression:       aws.String("attribute_exists(orderId) AND version = :v"),
+		ExpressionAttributeNames:  exprNames,
+		ExpressionAttributeValues: exprValues,
+		ReturnValues:              types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return apiError(http.StatusConflict, "Order was modified by another request")
+		}
+		log.Println("UpdateItem error:", err)
+		return apiError(http.StatusInternalServerError, "Failed to update order")
+	}
+
+	publishOrderEvent(ctx, "order.updated", mapToOrder(out.Attributes))
+	return response(http.StatusOK, "Order updated successfully")
+}
+
+// ---------- DELETE: Remove Order ----------
+func deleteOrder(ctx context.Context, orderId string, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	client := clientFromContext(ctx)
+	table := tableNameFromContext(ctx)
+
+	var body struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal([]byte(req.Body), &body); err != nil || body.Version <= 0 {
+		return apiError(http.StatusBadRequest, "version is required")
+	}
+
+	out, err := client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(table),
+		Key: map[string]types.AttributeValue{
+			"orderId": &types.AttributeValueMemberS{Value: orderId},
+		},
+		ConditionExpression: aws.String("attribute_exists(orderId) AND version = :v"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v": &types.AttributeValueMemberN{Value: intToString(body.Version)},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return apiError(http.StatusConflict, "Order was modified by another request")
+		}
+		log.Println("DeleteItem error:", err)
+		return apiError(http.StatusInternalServerError, "Failed to delete order")
+	}
+
+	publishOrderEvent(ctx, "order.deleted", mapToOrder(out.Attributes))
+	return response(http.StatusOK, "Order deleted successfully")
+}
+
+// batchItemResult reports the outcome of a single order within a batch write.
+type batchItemResult struct {
+	OrderID string `json:"orderId"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ---------- POST /orders:batch: Batch Create ----------
+func batchCreateOrders(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	client := clientFromContext(ctx)
+	table := tableNameFromContext(ctx)
+
+	var orders []Order
+	if err := json.Unmarshal([]byte(req.Body), &orders); err != nil {
+		return apiError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	results := make([]batchItemResult, len(orders))
+	for i := range orders {
+		if orders[i].CreatedAt == "" {
+			orders[i].CreatedAt = time.Now().UTC().Format(time.RFC3339)
+		}
+		orders[i].Version = 1
+		results[i] = batchItemResult{OrderID: orders[i].OrderID, Success: true}
+	}
+
+	const batchSize = 25
+	for start := 0; start < len(orders); start += batchSize {
+		end := start + batchSize
+		if end > len(orders) {
+			end = len(orders)
+		}
+
+		writeRequests := make([]types.WriteRequest, end-start)
+		for i, order := range orders[start:end] {
+			writeRequests[i] = types.WriteRequest{
+				PutRequest: &types.PutRequest{
+					Item: map[string]types.AttributeValue{
+						"orderId":      &types.AttributeValueMemberS{Value: order.OrderID},
+						"customerName": &types.AttributeValueMemberS{Value: order.CustomerName},
+						"product":      &types.AttributeValueMemberS{Value: order.Product},
+						"quantity":     &types.AttributeValueMemberN{Value: intToString(order.Quantity)},
+						"status":       &types.AttributeValueMemberS{Value: order.Status},
+						"createdAt":    &types.AttributeValueMemberS{Value: order.CreatedAt},
+						"version":      &types.AttributeValueMemberN{Value: intToString(order.Version)},
+					},
+				},
+			}
+		}
+
+		unprocessed, err := batchWriteWithRetry(ctx, client, table, writeRequests)
+		if err != nil {
+			log.Println("BatchWriteItem error:", err)
+			for i := start; i < end; i++ {
+				results[i].Success = false
+				results[i].Error = "Failed to write order"
+			}
+			continue
+		}
+
+		failedIDs := make(map[string]bool, len(unprocessed))
+		for _, wr := range unprocessed {
+			if wr.PutRequest == nil {
+				continue
+			}
+			if oid, ok := wr.PutRequest.Item["orderId"].(*types.AttributeValueMemberS); ok {
+				failedIDs[oid.Value] = true
+			}
+		}
+		for i := start; i < end; i++ {
+			if failedIDs[results[i].OrderID] {
+				results[i].Success = false
+				results[i].Error = "Failed to write order after retries"
+				continue
+			}
+			publishOrderEvent(ctx, "order.created", orders[i])
+		}
+	}
+
+	return writeJSON(http.StatusMultiStatus, map[string]interface{}{"results": results})
+}
+
+// batchWriteWithRetry issues BatchWriteItem and retries UnprocessedItems with
+// exponential backoff, returning whatever is still unprocessed once retries
+// are exhausted.
+func batchWriteWithRetry(ctx context.Context, client DynamoDBAPI, table string, requests []types.WriteRequest) ([]types.WriteRequest, error) {
+	const maxAttempts = 5
+	backoff := 100 * time.Millisecond
+
+	pending := requests
+	for attempt := 0; attempt < maxAttempts && len(pending) > 0; attempt++ {
+		out, err := client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{table: pending},
+		})
+		if err != nil {
+			return nil, err
+		}
+		pending = out.UnprocessedItems[table]
+		if len(pending) == 0 {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return pending, nil
+}
+
+// transactOp is a single put/update/delete within a POST /orders:transact request.
+type transactOp struct {
+	Action              string                 `json:"action"`
+	Order               *Order                 `json:"order,omitempty"`
+	OrderID             string                 `json:"orderId,omitempty"`
+	Fields              map[string]interface{} `json:"fields,omitempty"`
+	ConditionExpression string                 `json:"conditionExpression,omitempty"`
+	ConditionNames      map[string]string      `json:"conditionNames,omitempty"`
+	ConditionValues     map[string]interface{} `json:"conditionValues,omitempty"`
+}
+
+func (op transactOp) orderID() string {
+	if op.Order != nil {
+		return op.Order.OrderID
+	}
+	return op.OrderID
+}
+
+// conditionAttributeValues marshals a transactOp's conditionValues into the
+// AttributeValue map a ConditionExpression's placeholders resolve against.
+func conditionAttributeValues(values map[string]interface{}) (map[string]types.AttributeValue, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return attributevalue.MarshalMap(values)
+}
+
+// allowedConditionAttributes restricts a transact op's caller-supplied
+// ConditionExpression to the attributes the rest of this API already
+// exposes. This handler has no auth, so without a restriction a caller could
+// reference attributes they have no business reading and use the
+// 200-vs-409 outcome as an oracle on their values.
+var allowedConditionAttributes = map[string]bool{
+	"orderId": true,
+	"version": true,
+}
+
+// conditionExpressionKeywords are the DynamoDB condition-expression
+// operators/functions conditionExpressionIdentifiers may legitimately match
+// that aren't attribute names.
+var conditionExpressionKeywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "IN": true, "BETWEEN": true,
+	"ATTRIBUTE_EXISTS": true, "ATTRIBUTE_NOT_EXISTS": true, "ATTRIBUTE_TYPE": true,
+	"BEGINS_WITH": true, "CONTAINS": true, "SIZE": true,
+	"TRUE": true, "FALSE": true,
+}
+
+var conditionExpressionIdentifiers = regexp.MustCompile(`[:#]?[A-Za-z_][A-Za-z0-9_]*`)
+
+// validateConditionExpression rejects a ConditionExpression that references
+// any attribute outside allowedConditionAttributes, whether directly or via
+// an ExpressionAttributeNames alias.
+func validateConditionExpression(expr string, names map[string]string) error {
+	for _, attr := range names {
+		if !allowedConditionAttributes[attr] {
+			return fmt.Errorf("conditionExpression may only reference orderId or version")
+		}
+	}
+	for _, token := range conditionExpressionIdentifiers.FindAllString(expr, -1) {
+		if strings.HasPrefix(token, ":") || strings.HasPrefix(token, "#") {
+			continue
+		}
+		if conditionExpressionKeywords[strings.ToUpper(token)] {
+			continue
+		}
+		if !allowedConditionAttributes[token] {
+			return fmt.Errorf("conditionExpression may only reference orderId or version")
+		}
+	}
+	return nil
+}
+
+// transactFailure reports which operation failed which condition after a
+// TransactWriteItems cancellation.
+type transactFailure struct {
+	Index   int    `json:"index"`
+	OrderID string `json:"orderId,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ---------- POST /orders:transact: Transactional Write ----------
+func transactOrders(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	client := clientFromContext(ctx)
+	table := tableNameFromContext(ctx)
+
+	var body struct {
+		Operations []transactOp `json:"operations"`
+	}
+	if err := json.Unmarshal([]byte(req.Body), &body); err != nil {
+		return apiError(http.StatusBadRequest, "Invalid request body")
+	}
+	if len(body.Operations) == 0 || len(body.Operations) > 100 {
+		return apiError(http.StatusBadRequest, "operations must contain between 1 and 100 items")
+	}
+
+	// snapshots captures each update/delete op's order data (notably
+	// customerName, which websocket fanout routes on) before the transaction
+	// runs, since TransactWriteItems doesn't return item attributes and a
+	// deleted item can't be re-fetched afterward.
+	snapshots := make(map[int]Order, len(body.Operations))
+	for i, op := range body.Operations {
+		if op.Action != "update" && op.Action != "delete" {
+			continue
+		}
+		out, err := client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: aws.String(table),
+			Key: map[string]types.AttributeValue{
+				"orderId": &types.AttributeValueMemberS{Value: op.OrderID},
+			},
+		})
+		if err != nil {
+			log.Println("GetItem error (transact snapshot):", err)
+			continue
+		}
+		if out.Item != nil {
+			snapshots[i] = mapToOrder(out.Item)
+		}
+	}
+
+	items := make([]types.TransactWriteItem, len(body.Operations))
+	for i, op := range body.Operations {
+		switch op.Action {
+		case "put":
+			if op.Order == nil {
+				return apiError(http.StatusBadRequest, "put operation requires an order")
+			}
+			put := &types.Put{
+				TableName: aws.String(table),
+				Item: map[string]types.AttributeValue{
+					"orderId":      &types.AttributeValueMemberS{Value: op.Order.OrderID},
+					"customerName": &types.AttributeValueMemberS{Value: op.Order.CustomerName},
+					"product":      &types.AttributeValueMemberS{Value: op.Order.Product},
+					"quantity":     &types.AttributeValueMemberN{Value: intToString(op.Order.Quantity)},
+					"status":       &types.AttributeValueMemberS{Value: op.Order.Status},
+					"createdAt":    &types.AttributeValueMemberS{Value: op.Order.CreatedAt},
+					"version":      &types.AttributeValueMemberN{Value: intToString(op.Order.Version)},
+				},
+			}
+			if op.ConditionExpression != "" {
+				if err := validateConditionExpression(op.ConditionExpression, op.ConditionNames); err != nil {
+					return apiError(http.StatusBadRequest, err.Error())
+				}
+				put.ConditionExpression = aws.String(op.ConditionExpression)
+				condValues, err := conditionAttributeValues(op.ConditionValues)
+				if err != nil {
+					return apiError(http.StatusBadRequest, "invalid conditionValues")
+				}
+				put.ExpressionAttributeValues = condValues
+				put.ExpressionAttributeNames = op.ConditionNames
+			}
+			items[i] = types.TransactWriteItem{Put: put}
+
+		case "update":
+			if op.OrderID == "" || len(op.Fields) == 0 {
+				return apiError(http.StatusBadRequest, "update operation requires orderId and fields")
+			}
+			setClause, exprNames, exprValues, err := buildUpdateExpression(op.Fields)
+			if err != nil {
+				return apiError(http.StatusBadRequest, err.Error())
+			}
+			update := &types.Update{
+				TableName: aws.String(table),
+				Key: map[string]types.AttributeValue{
+					"orderId": &types.AttributeValueMemberS{Value: op.OrderID},
+				},
+				UpdateExpression:          aws.String("SET " + setClause),
+				ExpressionAttributeNames:  exprNames,
+				ExpressionAttributeValues: exprValues,
+			}
+			if op.ConditionExpression != "" {
+				if err := validateConditionExpression(op.ConditionExpression, op.ConditionNames); err != nil {
+					return apiError(http.StatusBadRequest, err.Error())
+				}
+				update.ConditionExpression = aws.String(op.ConditionExpression)
+				condValues, err := conditionAttributeValues(op.ConditionValues)
+				if err != nil {
+					return apiError(http.StatusBadRequest, "invalid conditionValues")
+				}
+				for k, v := range condValues {
+					exprValues[k] = v
+				}
+				for k, v := range op.ConditionNames {
+					exprNames[k] = v
+				}
+			}
+			items[i] = types.TransactWriteItem{Update: update}
+
+		case "delete":
+			if op.OrderID == "" {
+				return apiError(http.StatusBadRequest, "delete operation requires orderId")
+			}
+			del := &types.Delete{
+				TableName: aws.String(table),
+				Key: map[string]types.AttributeValue{
+					"orderId": &types.AttributeValueMemberS{Value: op.OrderID},
+				},
+			}
+			if op.ConditionExpression != "" {
+				if err := validateConditionExpression(op.ConditionExpression, op.ConditionNames); err != nil {
+					return apiError(http.StatusBadRequest, err.Error())
+				}
+				del.ConditionExpression = aws.String(op.ConditionExpression)
+				condValues, err := conditionAttributeValues(op.ConditionValues)
+				if err != nil {
+					return apiError(http.StatusBadRequest, "invalid conditionValues")
+				}
+				del.ExpressionAttributeValues = condValues
+				del.ExpressionAttributeNames = op.ConditionNames
+			}
+			items[i] = types.TransactWriteItem{Delete: del}
+
+		default:
+			return apiError(http.StatusBadRequest, "unknown operation action: "+op.Action)
+		}
+	}
+
+	_, err := client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		var canceledErr *types.TransactionCanceledException
+		if errors.As(err, &canceledErr) {
+			failures := []transactFailure{}
+			for i, reason := range canceledErr.CancellationReasons {
+				if reason.Code == nil || *reason.Code == "None" {
+					continue
+				}
+				failure := transactFailure{Index: i, Code: aws.ToString(reason.Code), Message: aws.ToString(reason.Message)}
+				if i < len(body.Operations) {
+					failure.OrderID = body.Operations[i].orderID()
+				}
+				failures = append(failures, failure)
+			}
+			return writeJSON(http.StatusConflict, map[string]interface{}{
+				"message":  "Transaction cancelled",
+				"failures": failures,
+			})
+		}
+		log.Println("TransactWriteItems error:", err)
+		return apiError(http.StatusInternalServerError, "Failed to execute transaction")
+	}
+
+	for i, op := range body.Operations {
+		switch op.Action {
+		case "put":
+			publishOrderEvent(ctx, "order.created", *op.Order)
+		case "update":
+			order := snapshots[i]
+			order.OrderID = op.OrderID
+			publishOrderEvent(ctx, "order.updated", order)
+		case "delete":
+			order := snapshots[i]
+			order.OrderID = op.OrderID
+			publishOrderEvent(ctx, "order.deleted", order)
+		}
+	}
+
+	return response(http.StatusOK, "Transaction executed successfully")
+}
+
+const (
+	defaultPageLimit  = 20
+	customerNameIndex = "customerName-index"
+	statusIndex       = "status-index"
+)
+
+// ---------- GET: List Orders (paginated, optionally GSI-backed) ----------
+func listOrders(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	client := clientFromContext(ctx)
+	table := tableNameFromContext(ctx)
+
+	limit := int32(defaultPageLimit)
+	if raw := req.QueryStringParameters["limit"]; raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			limit = int32(parsed)
+		}
+	}
+
+	startKey, err := decodeCursor(req.QueryStringParameters["cursor"])
+	if err != nil {
+		return apiError(http.StatusBadRequest, "Invalid cursor")
+	}
+
+	var (
+		items            []map[string]types.AttributeValue
+		lastEvaluatedKey map[string]types.AttributeValue
+		queryErr         error
+	)
+
+	switch {
+	case req.QueryStringParameters["customerName"] != "":
+		out, qErr := client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(table),
+			IndexName:              aws.String(customerNameIndex),
+			KeyConditionExpression: aws.String("customerName = :cn"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":cn": &types.AttributeValueMemberS{Value: req.QueryStringParameters["customerName"]},
+			},
+			Limit:             aws.Int32(limit),
+			ExclusiveStartKey: startKey,
+		})
+		if out != nil {
+			items, lastEvaluatedKey = out.Items, out.LastEvaluatedKey
+		}
+		queryErr = qErr
+
+	case req.QueryStringParameters["status"] != "":
+		out, qErr := client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(table),
+			IndexName:              aws.String(statusIndex),
+			KeyConditionExpression: aws.String("#status = :s"),
+			ExpressionAttributeNames: map[string]string{
+				"#status": "status",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":s": &types.AttributeValueMemberS{Value: req.QueryStringParameters["status"]},
+			},
+			Limit:             aws.Int32(limit),
+			ExclusiveStartKey: startKey,
+		})
+		if out != nil {
+			items, lastEvaluatedKey = out.Items, out.LastEvaluatedKey
+		}
+		queryErr = qErr
+
+	default:
+		out, sErr := client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(table),
+			Limit:             aws.Int32(limit),
+			ExclusiveStartKey: startKey,
+		})
+		if out != nil {
+			items, lastEvaluatedKey = out.Items, out.LastEvaluatedKey
+		}
+		queryErr = sErr
+	}
+
+	if queryErr != nil {
+		log.Println("listOrders error:", queryErr)
+		return apiError(http.StatusInternalServerError, "Failed to fetch orders")
+	}
+
+	orders := []Order{}
+	for _, item := range items {
+		orders = append(orders, mapToOrder(item))
+	}
+
+	nextCursor, err := encodeCursor(lastEvaluatedKey)
+	if err != nil {
+		log.Println("cursor encode error:", err)
+	}
+
+	return writeJSON(http.StatusOK, map[string]interface{}{
+		"orders":     orders,
+		"nextCursor": nextCursor,
+	})
+}
+
+// encodeCursor base64-encodes a LastEvaluatedKey so it can round-trip
+// through a query-string parameter as an opaque cursor.
+func encodeCursor(key map[string]types.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+	var plain map[string]interface{}
+	if err := attributevalue.UnmarshalMap(key, &plain); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(plain)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor reverses encodeCursor, yielding an ExclusiveStartKey.
+func decodeCursor(cursor string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	b, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	var plain map[string]interface{}
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return nil, err
+	}
+	return attributevalue.MarshalMap(plain)
+}
+
+// ---------- GET: Order by ID ----------
+func getOrderByID(ctx context.Context, orderId string) (events.APIGatewayProxyResponse, error) {
+	client := clientFromContext(ctx)
+	table := tableNameFromContext(ctx)
+
+	out, err := client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(table),
+		KeyConditionExpression: aws.String("orderId = :oid"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":oid": &types.AttributeValueMemberS{Value: orderId},
+		},
+	})
+	if err != nil {
+		log.Println("Query error:", err)
+		return apiError(http.StatusInternalServerError, "Failed to fetch order")
+	}
+
+	if len(out.Items) == 0 {
+		return apiError(http.StatusNotFound, "Order not found")
+	}
+
+	orders := []Order{}
+	for _, item := range out.Items {
+		orders = append(orders, mapToOrder(item))
+	}
+
+	return writeJSON(http.StatusOK, orders)
+}
+
+var allowedOrderStatuses = map[string]bool{
+	"CREATED":   true,
+	"PAID":      true,
+	"SHIPPED":   true,
+	"DELIVERED": true,
+	"CANCELLED": true,
+}
+
+// validateOrder checks the fields required of a newly created order,
+// returning one FieldError per violation so the caller can report them all
+// at once instead of failing fast on the first bad field.
+func validateOrder(order Order) []FieldError {
+	var details []FieldError
+
+	if strings.TrimSpace(order.OrderID) == "" {
+		details = append(details, FieldError{Field: "orderId", Message: "orderId is required"})
+	}
+	if strings.TrimSpace(order.CustomerName) == "" {
+		details = append(details, FieldError{Field: "customerName", Message: "customerName is required"})
+	}
+	if strings.TrimSpace(order.Product) == "" {
+		details = append(details, FieldError{Field: "product", Message: "product is required"})
+	}
+	if order.Quantity <= 0 {
+		details = append(details, FieldError{Field: "quantity", Message: "quantity must be positive"})
+	}
+	if !allowedOrderStatuses[order.Status] {
+		details = append(details, FieldError{Field: "status", Message: "status must be one of CREATED, PAID, SHIPPED, DELIVERED, CANCELLED"})
+	}
+
+	return details
+}
+
+// ---------- Helpers ----------
+
+// FieldError is a single field-level validation failure.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// APIError is the structured body returned for non-2xx responses. The 409
+// from POST /orders:transact is the exception: its body is a message plus a
+// list of per-operation failures.
+type APIError struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// corsHeaders builds the response headers every JSON response carries,
+// including CORS headers driven by env vars so the allowed origin/methods/
+// headers can be configured per deployment without a code change.
+func corsHeaders() map[string]string {
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+	if origin := os.Getenv("CORS_ALLOW_ORIGIN"); origin != "" {
+		headers["Access-Control-Allow-Origin"] = origin
+	}
+	if methods := os.Getenv("CORS_ALLOW_METHODS"); methods != "" {
+		headers["Access-Control-Allow-Methods"] = methods
+	}
+	if allowHeaders := os.Getenv("CORS_ALLOW_HEADERS"); allowHeaders != "" {
+		headers["Access-Control-Allow-Headers"] = allowHeaders
+	}
+	return headers
+}
+
+// writeJSON marshals v as the response body, always setting Content-Type
+// and the configured CORS headers.
+func writeJSON(status int, v interface{}) (events.APIGatewayProxyResponse, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Println("response marshal error:", err)
+		status = http.StatusInternalServerError
+		body = []byte(`{"code":"internal_error","message":"failed to encode response"}`)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Headers:    corsHeaders(),
+		Body:       string(body),
+	}, nil
+}
+
+// response writes a simple {"message": ...} body for success responses.
+func response(status int, msg string) (events.APIGatewayProxyResponse, error) {
+	return writeJSON(status, map[string]string{"message": msg})
+}
+
+// apiError writes a structured APIError body for non-2xx responses.
+func apiError(status int, message string, details ...FieldError) (events.APIGatewayProxyResponse, error) {
+	return writeJSON(status, APIError{
+		Code:    errorCode(status),
+		Message: message,
+		Details: details,
+	})
+}
+
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	default:
+		return "internal_error"
+	}
+}
+
+func intToString(i int) string {
+	return strconv.Itoa(i)
+}
+
+// mapToOrder converts a DynamoDB item into an Order, tolerating attributes
+// that are missing or of an unexpected type rather than panicking. Items read
+// back from a GSI query (customerName-index, status-index) only carry the
+// attributes that index's projection includes, so unlike the base-table
+// Scan/GetItem paths, fields here can legitimately be absent.
+func mapToOrder(item map[string]types.AttributeValue) Order {
+	var order Order
+	if v, ok := item["orderId"].(*types.AttributeValueMemberS); ok {
+		order.OrderID = v.Value
+	}
+	if v, ok := item["customerName"].(*types.AttributeValueMemberS); ok {
+		order.CustomerName = v.Value
+	}
+	if v, ok := item["product"].(*types.AttributeValueMemberS); ok {
+		order.Product = v.Value
+	}
+	if v, ok := item["quantity"].(*types.AttributeValueMemberN); ok {
+		order.Quantity = atoi(v.Value)
+	}
+	if v, ok := item["status"].(*types.AttributeValueMemberS); ok {
+		order.Status = v.Value
+	}
+	if v, ok := item["createdAt"].(*types.AttributeValueMemberS); ok {
+		order.CreatedAt = v.Value
+	}
+	if v, ok := item["version"].(*types.AttributeValueMemberN); ok {
+		order.Version = atoi(v.Value)
+	}
+	return order
+}
+
+func atoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
+
+// buildUpdateExpression turns an arbitrary field map into a DynamoDB SET
+// clause plus the expression attribute names/values it references. The
+// caller is responsible for prefixing the result with "SET ".
+func buildUpdateExpression(fields map[string]interface{}) (string, map[string]string, map[string]types.AttributeValue, error) {
+	names := map[string]string{}
+	values := map[string]types.AttributeValue{}
+	var sets []string
+
+	i := 0
+	for field, value := range fields {
+		nameKey := "#f" + strconv.Itoa(i)
+		valueKey := ":v" + strconv.Itoa(i)
+		names[nameKey] = field
+
+		switch v := value.(type) {
+		case string:
+			values[valueKey] = &types.AttributeValueMemberS{Value: v}
+		case float64:
+			values[valueKey] = &types.AttributeValueMemberN{Value: strconv.FormatFloat(v, 'f', -1, 64)}
+		default:
+			return "", nil, nil, fmt.Errorf("unsupported value for field %s", field)
+		}
+
+		sets = append(sets, nameKey+" = "+valueKey)
+		i++
+	}
+
+	return strings.Join(sets, ", "), names, values, nil
+}
+
+// lambdaHandler injects the production DynamoDB client and table name into
+// ctx before dispatching, since the context the Lambda runtime hands in on
+// each invocation doesn't carry our handler context keys.
+func lambdaHandler(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return handler(withHandlerContext(ctx, dbClient, tableName, snsClient, orderEventTopic), req)
+}
+
+func main() {
+	lambda.Start(lambdaHandler)
+
+	// test for getting order by order_id
+	// event := events.APIGatewayProxyRequest{
+	// 	HTTPMethod: "GET",
+	// 	PathParameters: map[string]string{
+	// 		"orderId": "d9dcde4b-b163-4176-8d1a-f49d270a2f5e",
+	// 	},
+	// }
+
+	// to get all orders
+	// event := events.APIGatewayProxyRequest{
+	// 	HTTPMethod: "GET",
+	// }
+
+	//to post an order
+	// event := events.APIGatewayProxyRequest{
+	// 	HTTPMethod: "POST",
+	// 	Body: `{
+	// 		"orderId": "20221",
+	// 		"customerName": "Siri",
+	// 		"product": "Lunch Box",
+	// 		"quantity": 1,
+	// 		"status": "CREATED"
+	// 	}`,
+	// }
+
+	// resp, err := lambdaHandler(context.Background(), event)
+	// if err != nil {
+	// 	log.Fatal(err)
+	// }
+
+	// log.Println("STATUS:", resp.StatusCode)
+	// log.Println("BODY:", resp.Body)
+}
@@ -0,0 +1,112 @@
+//go:build !websocket
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeDynamoDBClient is a DynamoDBAPI test double whose behavior is
+// configured per-test via the *Fn fields, so each test only needs to wire
+// up the handful of methods the path under test actually calls.
+type fakeDynamoDBClient struct {
+	putItemFn func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error)
+}
+
+func (f *fakeDynamoDBClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return f.putItemFn(ctx, params)
+}
+
+func (f *fakeDynamoDBClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return nil, nil
+}
+
+func (f *fakeDynamoDBClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return nil, nil
+}
+
+func (f *fakeDynamoDBClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return nil, nil
+}
+
+func (f *fakeDynamoDBClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return nil, nil
+}
+
+func (f *fakeDynamoDBClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	return nil, nil
+}
+
+func (f *fakeDynamoDBClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	return nil, nil
+}
+
+func (f *fakeDynamoDBClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	return nil, nil
+}
+
+// TestUpdateOrderVersionConflict verifies that a ConditionalCheckFailedException
+// from PutItem surfaces as a 409 rather than a generic 500.
+func TestUpdateOrderVersionConflict(t *testing.T) {
+	client := &fakeDynamoDBClient{
+		putItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			return nil, &types.ConditionalCheckFailedException{Message: nil}
+		},
+	}
+	ctx := withHandlerContext(context.Background(), client, "Orders", nil, "")
+
+	req := events.APIGatewayProxyRequest{
+		Body: `{"orderId":"o1","customerName":"Jane","product":"Widget","quantity":2,"status":"CREATED","version":1}`,
+	}
+
+	resp, err := updateOrder(ctx, "o1", req)
+	if err != nil {
+		t.Fatalf("updateOrder returned unexpected error: %v", err)
+	}
+	if resp.StatusCode != 409 {
+		t.Fatalf("expected status 409, got %d (body: %s)", resp.StatusCode, resp.Body)
+	}
+}
+
+// TestCursorRoundTrip verifies encodeCursor/decodeCursor round-trip a
+// LastEvaluatedKey through an opaque, query-string-safe cursor.
+func TestCursorRoundTrip(t *testing.T) {
+	key := map[string]types.AttributeValue{
+		"orderId": &types.AttributeValueMemberS{Value: "o1"},
+	}
+
+	cursor, err := encodeCursor(key)
+	if err != nil {
+		t.Fatalf("encodeCursor returned unexpected error: %v", err)
+	}
+	if cursor == "" {
+		t.Fatal("expected a non-empty cursor for a non-empty key")
+	}
+
+	decoded, err := decodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("decodeCursor returned unexpected error: %v", err)
+	}
+
+	orderID, ok := decoded["orderId"].(*types.AttributeValueMemberS)
+	if !ok || orderID.Value != "o1" {
+		t.Fatalf("expected decoded orderId %q, got %+v", "o1", decoded["orderId"])
+	}
+}
+
+// TestDecodeCursorEmpty verifies an empty cursor decodes to a nil
+// ExclusiveStartKey instead of erroring, matching listOrders' first-page case.
+func TestDecodeCursorEmpty(t *testing.T) {
+	decoded, err := decodeCursor("")
+	if err != nil {
+		t.Fatalf("decodeCursor(\"\") returned unexpected error: %v", err)
+	}
+	if decoded != nil {
+		t.Fatalf("expected nil key for empty cursor, got %+v", decoded)
+	}
+}
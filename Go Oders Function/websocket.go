@@ -0,0 +1,182 @@
+//go:build websocket
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewaymanagementapi"
+	apigwtypes "github.com/aws/aws-sdk-go-v2/service/apigatewaymanagementapi/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	connectionsTable         = "Connections"
+	connectionsCustomerIndex = "customerName-index"
+)
+
+var wsDBClient *dynamodb.Client
+
+func init() {
+	cfg, err := config.LoadDefaultConfig(context.TODO())
+	if err != nil {
+		log.Fatalf("failed to load AWS config: %v", err)
+	}
+	wsDBClient = dynamodb.NewFromConfig(cfg)
+}
+
+// orderEvent mirrors the payload the orders Lambda publishes to SNS.
+type orderEvent struct {
+	Type  string `json:"type"`
+	Order struct {
+		OrderID      string `json:"orderId"`
+		CustomerName string `json:"customerName"`
+	} `json:"order"`
+}
+
+// wsHandler is the entrypoint for the websocket fanout Lambda. It is invoked
+// either by API Gateway with a websocket frame ($connect/$disconnect/
+// subscribe) or by the order-events SNS subscription, so the event is
+// unmarshalled generically and dispatched on shape.
+func wsHandler(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var wsReq events.APIGatewayWebsocketProxyRequest
+	if err := json.Unmarshal(raw, &wsReq); err == nil && wsReq.RequestContext.RouteKey != "" {
+		return handleWebsocketFrame(ctx, wsReq)
+	}
+
+	var snsEvent events.SNSEvent
+	if err := json.Unmarshal(raw, &snsEvent); err != nil {
+		return nil, err
+	}
+	return nil, handleFanout(ctx, snsEvent)
+}
+
+// handleWebsocketFrame manages connection lifecycle and per-customer
+// subscriptions, keyed by connection ID in the Connections table.
+func handleWebsocketFrame(ctx context.Context, req events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	connectionID := req.RequestContext.ConnectionID
+
+	switch req.RequestContext.RouteKey {
+	case "$connect":
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+
+	case "$disconnect":
+		_, err := wsDBClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(connectionsTable),
+			Key: map[string]types.AttributeValue{
+				"connectionId": &types.AttributeValueMemberS{Value: connectionID},
+			},
+		})
+		if err != nil {
+			log.Println("DeleteItem error:", err)
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusInternalServerError}, nil
+		}
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+
+	case "subscribe":
+		var body struct {
+			CustomerName string `json:"customerName"`
+		}
+		if err := json.Unmarshal([]byte(req.Body), &body); err != nil || body.CustomerName == "" {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusBadRequest, Body: "customerName is required"}, nil
+		}
+
+		_, err := wsDBClient.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(connectionsTable),
+			Item: map[string]types.AttributeValue{
+				"connectionId": &types.AttributeValueMemberS{Value: connectionID},
+				"customerName": &types.AttributeValueMemberS{Value: body.CustomerName},
+			},
+		})
+		if err != nil {
+			log.Println("PutItem error:", err)
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusInternalServerError}, nil
+		}
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+
+	default:
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusBadRequest, Body: "Unknown route"}, nil
+	}
+}
+
+// handleFanout pushes an order lifecycle event to every websocket connection
+// subscribed to the order's customer.
+func handleFanout(ctx context.Context, snsEvent events.SNSEvent) error {
+	endpoint := os.Getenv("WEBSOCKET_API_ENDPOINT")
+	if endpoint == "" {
+		return errors.New("WEBSOCKET_API_ENDPOINT is not configured")
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return err
+	}
+	mgmtClient := apigatewaymanagementapi.NewFromConfig(cfg, func(o *apigatewaymanagementapi.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+	})
+
+	for _, record := range snsEvent.Records {
+		var evt orderEvent
+		if err := json.Unmarshal([]byte(record.SNS.Message), &evt); err != nil {
+			log.Println("order event unmarshal error:", err)
+			continue
+		}
+		pushToSubscribers(ctx, mgmtClient, evt.Order.CustomerName, []byte(record.SNS.Message))
+	}
+
+	return nil
+}
+
+func pushToSubscribers(ctx context.Context, mgmtClient *apigatewaymanagementapi.Client, customerName string, payload []byte) {
+	out, err := wsDBClient.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(connectionsTable),
+		IndexName:              aws.String(connectionsCustomerIndex),
+		KeyConditionExpression: aws.String("customerName = :cn"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":cn": &types.AttributeValueMemberS{Value: customerName},
+		},
+	})
+	if err != nil {
+		log.Println("Query error:", err)
+		return
+	}
+
+	for _, item := range out.Items {
+		connectionID := item["connectionId"].(*types.AttributeValueMemberS).Value
+
+		_, err := mgmtClient.PostToConnection(ctx, &apigatewaymanagementapi.PostToConnectionInput{
+			ConnectionId: aws.String(connectionID),
+			Data:         payload,
+		})
+		if err == nil {
+			continue
+		}
+
+		var goneErr *apigwtypes.GoneException
+		if errors.As(err, &goneErr) {
+			_, _ = wsDBClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+				TableName: aws.String(connectionsTable),
+				Key: map[string]types.AttributeValue{
+					"connectionId": &types.AttributeValueMemberS{Value: connectionID},
+				},
+			})
+			continue
+		}
+		log.Println("PostToConnection error:", err)
+	}
+}
+
+func main() {
+	lambda.Start(wsHandler)
+}